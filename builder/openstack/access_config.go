@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openstack
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+)
+
+// AccessConfig holds the credentials and endpoint settings needed to
+// authenticate against an OpenStack cloud, and is embedded into Config so
+// every step can reach the service clients it needs.
+type AccessConfig struct {
+	Username         string `mapstructure:"username"`
+	Password         string `mapstructure:"password"`
+	IdentityEndpoint string `mapstructure:"identity_endpoint"`
+	ProjectName      string `mapstructure:"project_name"`
+	DomainName       string `mapstructure:"domain_name"`
+	Region           string `mapstructure:"region"`
+	Insecure         bool   `mapstructure:"insecure"`
+
+	osClient *gophercloud.ProviderClient
+}
+
+// Client returns an authenticated OpenStack provider client, caching it
+// across calls so every service client shares the same token.
+func (c *AccessConfig) Client() (*gophercloud.ProviderClient, error) {
+	if c.osClient != nil {
+		return c.osClient, nil
+	}
+
+	authOpts := gophercloud.AuthOptions{
+		IdentityEndpoint: c.IdentityEndpoint,
+		Username:         c.Username,
+		Password:         c.Password,
+		TenantName:       c.ProjectName,
+		DomainName:       c.DomainName,
+	}
+
+	client, err := openstack.AuthenticatedClient(authOpts)
+	if err != nil {
+		return nil, fmt.Errorf("Error authenticating with OpenStack: %s", err)
+	}
+
+	c.osClient = client
+	return client, nil
+}
+
+func (c *AccessConfig) endpointOpts() gophercloud.EndpointOpts {
+	return gophercloud.EndpointOpts{Region: c.Region}
+}
+
+// blockStorageV3Client returns a Cinder v3 client, used by the
+// volume/snapshot steps.
+func (c *AccessConfig) blockStorageV3Client() (*gophercloud.ServiceClient, error) {
+	client, err := c.Client()
+	if err != nil {
+		return nil, err
+	}
+	return openstack.NewBlockStorageV3(client, c.endpointOpts())
+}
+
+// imageV2Client returns a Glance v2 client, used to read the source image's
+// minimum disk size.
+func (c *AccessConfig) imageV2Client() (*gophercloud.ServiceClient, error) {
+	client, err := c.Client()
+	if err != nil {
+		return nil, err
+	}
+	return openstack.NewImageServiceV2(client, c.endpointOpts())
+}
+
+// computeV2Client returns a Nova v2 client; no step in this package calls
+// it.
+func (c *AccessConfig) computeV2Client() (*gophercloud.ServiceClient, error) {
+	client, err := c.Client()
+	if err != nil {
+		return nil, err
+	}
+	return openstack.NewComputeV2(client, c.endpointOpts())
+}
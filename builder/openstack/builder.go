@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openstack
+
+import (
+	"context"
+
+	"github.com/hashicorp/packer-plugin-sdk/multistep"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+const BuilderId = "hashicorp.openstack"
+
+// Builder assembles the OpenStack build steps from Config and runs them.
+type Builder struct {
+	config Config
+	runner multistep.Runner
+}
+
+func (b *Builder) Prepare(raws ...interface{}) ([]string, []string, error) {
+	warnings, err := b.config.Prepare(raws...)
+	if err != nil {
+		return nil, warnings, err
+	}
+	return nil, warnings, nil
+}
+
+func (b *Builder) Run(ctx context.Context, ui packersdk.Ui, hook packersdk.Hook) (packersdk.Artifact, error) {
+	state := new(multistep.BasicStateBag)
+	state.Put("config", &b.config)
+	state.Put("hook", hook)
+	state.Put("ui", ui)
+	state.Put("source_image", b.config.SourceImage)
+
+	rootDeviceName := "/dev/vda"
+	if len(b.config.BlockDevices) > 0 && b.config.BlockDevices[0].DeviceName != "" {
+		rootDeviceName = b.config.BlockDevices[0].DeviceName
+	}
+
+	steps := []multistep.Step{
+		&StepCreateVolumes{
+			UseBlockStorageVolume: b.config.UseBlockStorageVolume,
+			BlockDevices:          b.config.BlockDevices,
+		},
+		// ... source server and communicator steps run here; no step in this
+		// package reads the "block_device_mappings" state value put above ...
+		&StepExtendVolume{
+			UseBlockStorageVolume: b.config.UseBlockStorageVolume,
+			EnableOnlineResize:    b.config.EnableOnlineResize,
+			VolumeSize:            b.config.VolumeSize,
+			RootDeviceName:        rootDeviceName,
+		},
+		// ... provisioning steps run here ...
+		&StepCreateSnapshot{
+			UseBlockStorageVolume: b.config.UseBlockStorageVolume,
+			CreateSnapshot:        b.config.CreateSnapshot,
+			SnapshotName:          b.config.SnapshotName,
+			SnapshotDescription:   b.config.SnapshotDescription,
+			SnapshotMetadata:      b.config.SnapshotMetadata,
+		},
+		// ... image creation steps run here ...
+	}
+
+	b.runner = &multistep.BasicRunner{Steps: steps}
+	b.runner.Run(ctx, state)
+
+	if rawErr, ok := state.GetOk("error"); ok {
+		return nil, rawErr.(error)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openstack
+
+import (
+	"fmt"
+	"time"
+
+	packerConfig "github.com/hashicorp/packer-plugin-sdk/template/config"
+	"github.com/hashicorp/packer-plugin-sdk/template/interpolate"
+)
+
+// Config is the configuration structure for the OpenStack builder, decoded
+// directly from a user's packer template.
+type Config struct {
+	AccessConfig `mapstructure:",squash"`
+
+	// SourceImage is the ID of the Glance image to boot the build instance
+	// from.
+	SourceImage string `mapstructure:"source_image"`
+
+	// UseBlockStorageVolume, when true, boots the instance from a Cinder
+	// volume instead of the image's ephemeral disk.
+	UseBlockStorageVolume  bool   `mapstructure:"use_blockstorage_volume"`
+	VolumeName             string `mapstructure:"volume_name"`
+	VolumeType             string `mapstructure:"volume_type"`
+	VolumeAvailabilityZone string `mapstructure:"availability_zone"`
+	VolumeSize             int    `mapstructure:"volume_size"`
+	VolumeDescription      string `mapstructure:"volume_description"`
+	VolumeSnapshotID       string `mapstructure:"volume_snapshot_id"`
+	VolumeSourceVolID      string `mapstructure:"volume_source_vol_id"`
+	VolumeID               string `mapstructure:"volume_id"`
+
+	ImageMetadata map[string]string `mapstructure:"image_metadata"`
+
+	// CreateSnapshot, when true, snapshots the built volume after
+	// provisioning and stores the snapshot ID in state as "snapshot_id";
+	// Builder.Run does not return it as an artifact.
+	CreateSnapshot      bool              `mapstructure:"create_snapshot"`
+	SnapshotName        string            `mapstructure:"snapshot_name"`
+	SnapshotDescription string            `mapstructure:"snapshot_description"`
+	SnapshotMetadata    map[string]string `mapstructure:"snapshot_metadata"`
+
+	// EnableOnlineResize, when true, extends the volume after the instance
+	// is running if VolumeSize is larger than the volume as created.
+	EnableOnlineResize bool `mapstructure:"enable_online_resize"`
+
+	// BlockDevices configures one or more Cinder volumes to attach to the
+	// instance. When empty, StepCreateVolumes falls back to a single root
+	// volume built from the legacy Volume* fields above.
+	BlockDevices []BlockDevice `mapstructure:"block_device"`
+
+	// VolumeWaitTimeout and VolumePollInterval tune WaitForVolume's
+	// exponential-backoff poller. Both fall back to package defaults
+	// (defaultVolumeWaitTimeout, defaultVolumePollInterval) when zero.
+	VolumeWaitTimeout  time.Duration `mapstructure:"volume_wait_timeout"`
+	VolumePollInterval time.Duration `mapstructure:"volume_poll_interval"`
+
+	ctx interpolate.Context
+}
+
+// Prepare decodes raw template data into Config and validates it.
+func (c *Config) Prepare(raws ...interface{}) ([]string, error) {
+	if err := packerConfig.Decode(c, &packerConfig.DecodeOpts{
+		Interpolate:        true,
+		InterpolateContext: &c.ctx,
+	}, raws...); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	if c.SourceImage == "" && c.VolumeSnapshotID == "" && c.VolumeSourceVolID == "" && c.VolumeID == "" && len(c.BlockDevices) == 0 {
+		errs = append(errs, fmt.Errorf("source_image, volume_snapshot_id, volume_source_vol_id, volume_id, or block_device is required"))
+	}
+
+	if c.CreateSnapshot && !c.UseBlockStorageVolume {
+		errs = append(errs, fmt.Errorf("create_snapshot requires use_blockstorage_volume to be true"))
+	}
+
+	if len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, err := range errs {
+			messages[i] = err.Error()
+		}
+		return messages, fmt.Errorf("%d error(s) occurred", len(errs))
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,19 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openstack
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+)
+
+// GetVolumeSize returns the size, in GB, that a volume built from imageID
+// needs to be, derived from the image's minimum disk requirement.
+func GetVolumeSize(imageClient *gophercloud.ServiceClient, imageID string) (int, error) {
+	image, err := images.Get(imageClient, imageID).Extract()
+	if err != nil {
+		return 0, err
+	}
+	return image.MinDiskGigabytes, nil
+}
@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
+	"github.com/hashicorp/packer-plugin-sdk/multistep"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+// StepCreateSnapshot snapshots the built volume so that it can be used as
+// an artifact on its own, or as the source for future builds via
+// volume_snapshot_id. It runs after provisioning and before image creation,
+// and only when UseBlockStorageVolume is set.
+type StepCreateSnapshot struct {
+	UseBlockStorageVolume bool
+	CreateSnapshot        bool
+	SnapshotName          string
+	SnapshotDescription   string
+	SnapshotMetadata      map[string]string
+	snapshotID            string
+}
+
+func (s *StepCreateSnapshot) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	// Proceed only if block storage volume is required and a snapshot was requested.
+	if !s.UseBlockStorageVolume || !s.CreateSnapshot {
+		return multistep.ActionContinue
+	}
+
+	config := state.Get("config").(*Config)
+	ui := state.Get("ui").(packersdk.Ui)
+	volumeID := state.Get("volume_id").(string)
+
+	blockStorageClient, err := config.blockStorageV3Client()
+	if err != nil {
+		err = fmt.Errorf("Error initializing block storage client: %s", err)
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	ui.Say("Creating snapshot...")
+	snapshotOpts := snapshots.CreateOpts{
+		VolumeID:    volumeID,
+		Name:        s.SnapshotName,
+		Description: s.SnapshotDescription,
+		Metadata:    s.SnapshotMetadata,
+		Force:       true,
+	}
+	snapshot, err := snapshots.Create(blockStorageClient, snapshotOpts).Extract()
+	if err != nil {
+		err := fmt.Errorf("Error creating snapshot: %s", err)
+		state.Put("error", err)
+		ui.Error(err.Error())
+		return multistep.ActionHalt
+	}
+
+	// Put snapshot ID here for clean up.
+	s.snapshotID = snapshot.ID
+
+	// Wait for the snapshot to become available.
+	ui.Say(fmt.Sprintf("Waiting for snapshot %s (snapshot id: %s) to become available...", s.SnapshotName, snapshot.ID))
+	if err := WaitForSnapshot(ctx, blockStorageClient, snapshot.ID, config.VolumeWaitTimeout, config.VolumePollInterval); err != nil {
+		err := fmt.Errorf("Error waiting for snapshot: %s", err)
+		state.Put("error", err)
+		ui.Error(err.Error())
+		return multistep.ActionHalt
+	}
+
+	// Set the snapshot ID in the state as an alternative artifact.
+	ui.Message(fmt.Sprintf("Snapshot ID: %s", snapshot.ID))
+	state.Put("snapshot_id", snapshot.ID)
+
+	return multistep.ActionContinue
+}
+
+func (s *StepCreateSnapshot) Cleanup(state multistep.StateBag) {
+	// Snapshots are a build artifact, not transient state, so they are
+	// never removed here even if a later step fails.
+	if s.snapshotID == "" {
+		return
+	}
+
+	ui := state.Get("ui").(packersdk.Ui)
+	ui.Message(fmt.Sprintf("Snapshot %s was not cleaned up and remains available as an artifact", s.snapshotID))
+}
+
+// WaitForSnapshot polls a Cinder snapshot until it reaches the "available"
+// status, sharing WaitForVolume's exponential-backoff-with-jitter poller: it
+// honors ctx cancellation, fails fast on terminal states, and retries
+// transient gophercloud API errors. A timeout or pollInterval of zero falls
+// back to the same package defaults WaitForVolume uses.
+func WaitForSnapshot(ctx context.Context, client *gophercloud.ServiceClient, snapshotID string, timeout, pollInterval time.Duration) error {
+	if timeout <= 0 {
+		timeout = defaultVolumeWaitTimeout
+	}
+	if pollInterval <= 0 {
+		pollInterval = defaultVolumePollInterval
+	}
+
+	deadline := time.Now().Add(timeout)
+	backoff := pollInterval
+
+	for {
+		snapshot, err := snapshots.Get(client, snapshotID).Extract()
+		if err != nil {
+			if !isTransientVolumeError(err) {
+				return fmt.Errorf("error getting snapshot %s: %s", snapshotID, err)
+			}
+		} else {
+			switch snapshot.Status {
+			case "available":
+				return nil
+			case "error", "error_deleting":
+				return fmt.Errorf("snapshot %s entered status %q", snapshotID, snapshot.Status)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for snapshot %s", timeout, snapshotID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jitteredWait(backoff)):
+		}
+
+		backoff = nextBackoff(backoff)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openstack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/bootfromvolume"
+	"github.com/hashicorp/packer-plugin-sdk/multistep"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+// BlockDevice describes a single Cinder volume to attach to the instance,
+// either as the root device built from the source image or as an extra
+// scratch/data disk. It maps directly onto a Nova block_device_mapping_v2
+// entry once the corresponding volume has been created.
+type BlockDevice struct {
+	// SourceType is one of "image", "blank", "snapshot", or "volume".
+	SourceType string `mapstructure:"source_type"`
+	// SourceID is the image, snapshot, or volume ID to build the volume
+	// from. Unused when SourceType is "blank".
+	SourceID string `mapstructure:"source_id"`
+	// VolumeID reuses an already-existing volume instead of creating one.
+	VolumeID         string `mapstructure:"volume_id"`
+	VolumeName       string `mapstructure:"name"`
+	VolumeSize       int    `mapstructure:"size"`
+	VolumeType       string `mapstructure:"volume_type"`
+	AvailabilityZone string `mapstructure:"availability_zone"`
+	Description      string `mapstructure:"description"`
+	// DeviceName labels this device in build output, and for the first
+	// (root) device it is also the guest device StepExtendVolume grows.
+	// gophercloud's bootfromvolume binding has no way to request a specific
+	// guest device name from Nova, so this is not sent in the block device
+	// mapping.
+	DeviceName          string            `mapstructure:"device_name"`
+	DeleteOnTermination bool              `mapstructure:"delete_on_termination"`
+	VolumeMetadata      map[string]string `mapstructure:"metadata"`
+}
+
+// StepCreateVolumes creates one Cinder volume per configured BlockDevice and
+// records the resulting block_device_mapping_v2 entries under the
+// "block_device_mappings" state key; no step in this package passes them to
+// Nova. The first device is treated as the
+// root volume: its ID is also stored as "volume_id" for steps that only deal
+// with a single, primary volume (StepCreateSnapshot, StepExtendVolume).
+type StepCreateVolumes struct {
+	UseBlockStorageVolume bool
+	BlockDevices          []BlockDevice
+	volumeIDs             []string
+}
+
+func (s *StepCreateVolumes) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	// Proceed only if block storage volume is required.
+	if !s.UseBlockStorageVolume {
+		return multistep.ActionContinue
+	}
+
+	config := state.Get("config").(*Config)
+	ui := state.Get("ui").(packersdk.Ui)
+	sourceImage := state.Get("source_image").(string)
+
+	blockStorageClient, err := config.blockStorageV3Client()
+	if err != nil {
+		err = fmt.Errorf("Error initializing block storage client: %s", err)
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	blockDevices := s.BlockDevices
+	if len(blockDevices) == 0 {
+		// No explicit block devices configured: fall back to a single root
+		// volume, preserving pre-multi-volume behavior (including building
+		// from a snapshot/source volume or reusing an existing volume).
+		root := BlockDevice{
+			SourceType:          "image",
+			SourceID:            sourceImage,
+			VolumeID:            config.VolumeID,
+			VolumeName:          config.VolumeName,
+			VolumeSize:          config.VolumeSize,
+			VolumeType:          config.VolumeType,
+			AvailabilityZone:    config.VolumeAvailabilityZone,
+			Description:         config.VolumeDescription,
+			DeviceName:          "/dev/vda",
+			DeleteOnTermination: true,
+			VolumeMetadata:      config.ImageMetadata,
+		}
+
+		switch {
+		case config.VolumeSnapshotID != "":
+			root.SourceType = "snapshot"
+			root.SourceID = config.VolumeSnapshotID
+		case config.VolumeSourceVolID != "":
+			root.SourceType = "volume"
+			root.SourceID = config.VolumeSourceVolID
+		}
+
+		blockDevices = []BlockDevice{root}
+	}
+
+	var mappings []bootfromvolume.BlockDevice
+	var volumeIDs []string
+
+	for i, device := range blockDevices {
+		volumeID, err := s.createOrReuseVolume(ctx, blockStorageClient, config, ui, device)
+		if err != nil {
+			state.Put("error", err)
+			ui.Error(err.Error())
+			return multistep.ActionHalt
+		}
+
+		volumeIDs = append(volumeIDs, volumeID)
+		if i == 0 {
+			state.Put("volume_id", volumeID)
+		}
+
+		// gophercloud's bootfromvolume.BlockDevice has no way to request a
+		// specific guest device name (Nova's block_device_mapping_v2
+		// "device_name" isn't exposed by this binding); ordering is conveyed
+		// via BootIndex instead, with the root device first.
+		bootIndex := -1
+		if i == 0 {
+			bootIndex = 0
+		}
+
+		mappings = append(mappings, bootfromvolume.BlockDevice{
+			SourceType:          bootfromvolume.SourceType("volume"),
+			UUID:                volumeID,
+			DestinationType:     bootfromvolume.DestinationVolume,
+			BootIndex:           bootIndex,
+			DeleteOnTermination: device.DeleteOnTermination,
+		})
+	}
+
+	state.Put("volume_ids", volumeIDs)
+	state.Put("block_device_mappings", mappings)
+
+	return multistep.ActionContinue
+}
+
+// createOrReuseVolume creates a single Cinder volume for the given
+// BlockDevice, or attaches to an already-existing one when VolumeID is set.
+func (s *StepCreateVolumes) createOrReuseVolume(ctx context.Context, blockStorageClient *gophercloud.ServiceClient, config *Config, ui packersdk.Ui, device BlockDevice) (string, error) {
+	if device.VolumeID != "" {
+		ui.Say(fmt.Sprintf("Using existing volume: %s", device.VolumeID))
+		if err := WaitForVolume(ctx, blockStorageClient, device.VolumeID, config.VolumeWaitTimeout, config.VolumePollInterval); err != nil {
+			return "", fmt.Errorf("Error waiting for volume: %s", err)
+		}
+		ui.Message(fmt.Sprintf("Volume ID: %s", device.VolumeID))
+		return device.VolumeID, nil
+	}
+
+	volumeSize := device.VolumeSize
+
+	// Get the needed volume size from the source image, unless the volume
+	// is blank, or is being created from a snapshot or another volume,
+	// which already carry their own size.
+	if volumeSize == 0 && device.SourceType == "image" {
+		imageClient, err := config.imageV2Client()
+		if err != nil {
+			return "", fmt.Errorf("Error initializing image client: %s", err)
+		}
+
+		volumeSize, err = GetVolumeSize(imageClient, device.SourceID)
+		if err != nil {
+			return "", fmt.Errorf("Error creating volume: %s", err)
+		}
+	}
+
+	ui.Say(fmt.Sprintf("Creating %s volume...", device.DeviceName))
+	volumeOpts := volumes.CreateOpts{
+		Size:             volumeSize,
+		VolumeType:       device.VolumeType,
+		AvailabilityZone: device.AvailabilityZone,
+		Name:             device.VolumeName,
+		Description:      device.Description,
+		Metadata:         device.VolumeMetadata,
+	}
+
+	switch device.SourceType {
+	case "snapshot":
+		volumeOpts.SnapshotID = device.SourceID
+	case "volume":
+		volumeOpts.SourceVolID = device.SourceID
+	case "image":
+		volumeOpts.ImageID = device.SourceID
+	case "blank":
+		// No source: an empty volume.
+	default:
+		return "", fmt.Errorf("invalid source_type %q for block device %q: must be one of image, blank, snapshot, volume", device.SourceType, device.DeviceName)
+	}
+
+	volume, err := volumes.Create(blockStorageClient, volumeOpts).Extract()
+	if err != nil {
+		return "", fmt.Errorf("Error creating volume: %s", err)
+	}
+
+	// Record the volume for clean up even if it fails to become available.
+	s.volumeIDs = append(s.volumeIDs, volume.ID)
+
+	ui.Say(fmt.Sprintf("Waiting for volume %s (volume id: %s) to become available...", device.DeviceName, volume.ID))
+	if err := WaitForVolume(ctx, blockStorageClient, volume.ID, config.VolumeWaitTimeout, config.VolumePollInterval); err != nil {
+		return "", fmt.Errorf("Error waiting for volume: %s", err)
+	}
+
+	ui.Message(fmt.Sprintf("Volume ID: %s", volume.ID))
+	return volume.ID, nil
+}
+
+func (s *StepCreateVolumes) Cleanup(state multistep.StateBag) {
+	if len(s.volumeIDs) == 0 {
+		return
+	}
+
+	config := state.Get("config").(*Config)
+	ui := state.Get("ui").(packersdk.Ui)
+
+	blockStorageClient, err := config.blockStorageV3Client()
+	if err != nil {
+		ui.Error(fmt.Sprintf(
+			"Error cleaning up volumes. Please delete the volumes manually: %v", s.volumeIDs))
+		return
+	}
+
+	for _, volumeID := range s.volumeIDs {
+		ui.Say(fmt.Sprintf("Deleting volume: %s ...", volumeID))
+
+		// Delete the volume in any status if exists.
+		if err := volumes.Delete(blockStorageClient, volumeID, volumes.DeleteOpts{}).ExtractErr(); err != nil {
+			ui.Error(fmt.Sprintf(
+				"Error cleaning up volume %q: %s. This may need manual deletion.", volumeID, err))
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openstack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/volumeactions"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/hashicorp/packer-plugin-sdk/multistep"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+// StepExtendVolume grows the root volume to VolumeSize after the instance is
+// running, then grows the guest's root partition and filesystem. It acts
+// only when the root volume is smaller than VolumeSize: a volume reused via
+// volume_id, or a block_device root whose size was left unset and so was
+// derived from the source image's minimum disk. StepCreateVolumes creates
+// the legacy single root volume at VolumeSize whenever that is set, so in
+// that case there is nothing to grow.
+type StepExtendVolume struct {
+	UseBlockStorageVolume bool
+	EnableOnlineResize    bool
+	VolumeSize            int
+	// RootDeviceName is the guest device the root volume is attached as
+	// (config.BlockDevices[0].DeviceName). Defaults to "/dev/vda" when unset,
+	// matching StepCreateVolumes' default root block device.
+	RootDeviceName string
+}
+
+func (s *StepExtendVolume) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	// Proceed only if block storage volume is required and online resize was requested.
+	if !s.UseBlockStorageVolume || !s.EnableOnlineResize {
+		return multistep.ActionContinue
+	}
+
+	config := state.Get("config").(*Config)
+	ui := state.Get("ui").(packersdk.Ui)
+	comm := state.Get("communicator").(packersdk.Communicator)
+	volumeID := state.Get("volume_id").(string)
+
+	blockStorageClient, err := config.blockStorageV3Client()
+	if err != nil {
+		err = fmt.Errorf("Error initializing block storage client: %s", err)
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	volume, err := volumes.Get(blockStorageClient, volumeID).Extract()
+	if err != nil {
+		err := fmt.Errorf("Error reading current volume size: %s", err)
+		state.Put("error", err)
+		ui.Error(err.Error())
+		return multistep.ActionHalt
+	}
+
+	if s.VolumeSize <= volume.Size {
+		return multistep.ActionContinue
+	}
+
+	ui.Say(fmt.Sprintf("Extending volume %s from %d GB to %d GB...", volumeID, volume.Size, s.VolumeSize))
+	extendOpts := volumeactions.ExtendSizeOpts{
+		NewSize: s.VolumeSize,
+	}
+	if err := volumeactions.ExtendSize(blockStorageClient, volumeID, extendOpts).ExtractErr(); err != nil {
+		err := fmt.Errorf("Error extending volume: %s", err)
+		state.Put("error", err)
+		ui.Error(err.Error())
+		return multistep.ActionHalt
+	}
+
+	if err := WaitForVolume(ctx, blockStorageClient, volumeID, config.VolumeWaitTimeout, config.VolumePollInterval); err != nil {
+		err := fmt.Errorf("Error waiting for volume to finish extending: %s", err)
+		state.Put("error", err)
+		ui.Error(err.Error())
+		return multistep.ActionHalt
+	}
+
+	rootDeviceName := s.RootDeviceName
+	if rootDeviceName == "" {
+		rootDeviceName = "/dev/vda"
+	}
+
+	ui.Say("Growing the partition and filesystem on the guest...")
+	if err := growGuestFilesystem(ctx, comm, ui, rootDeviceName); err != nil {
+		err := fmt.Errorf("Error growing guest filesystem: %s", err)
+		state.Put("error", err)
+		ui.Error(err.Error())
+		return multistep.ActionHalt
+	}
+
+	return multistep.ActionContinue
+}
+
+func (s *StepExtendVolume) Cleanup(multistep.StateBag) {
+	// Nothing to clean up; resizing a volume is not reversible and the
+	// volume itself, when the build created it, is torn down by
+	// StepCreateVolumes.
+}
+
+// growGuestFilesystem grows the root partition and filesystem on the guest
+// to consume the full size of the underlying volume after an online resize.
+// growpart and the filesystem resize are run as separate checked commands:
+// growpart's exit status is informational only, since it legitimately
+// returns non-zero (NOCHANGE) once the partition already spans the volume,
+// but the filesystem resize must succeed or the step fails, since that is
+// the step that actually reclaims the grown space for the guest.
+func growGuestFilesystem(ctx context.Context, comm packersdk.Communicator, ui packersdk.Ui, deviceName string) error {
+	partition := deviceName + "1"
+
+	growpart := &packersdk.RemoteCmd{Command: fmt.Sprintf("sudo growpart %s 1", deviceName)}
+	if err := comm.Start(ctx, growpart); err != nil {
+		return fmt.Errorf("failed to run growpart: %s", err)
+	}
+	growpart.Wait()
+	if growpart.ExitStatus() != 0 {
+		ui.Message(fmt.Sprintf("growpart on %s exited with status %d; it may already span the volume", deviceName, growpart.ExitStatus()))
+	}
+
+	resize := &packersdk.RemoteCmd{Command: fmt.Sprintf("sudo resize2fs %s || sudo xfs_growfs /", partition)}
+	if err := comm.Start(ctx, resize); err != nil {
+		return fmt.Errorf("failed to run filesystem resize: %s", err)
+	}
+	resize.Wait()
+	if resize.ExitStatus() != 0 {
+		return fmt.Errorf("filesystem resize on %s exited with status %d", partition, resize.ExitStatus())
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+)
+
+const (
+	defaultVolumeWaitTimeout  = 30 * time.Minute
+	defaultVolumePollInterval = 2 * time.Second
+	maxVolumePollInterval     = 30 * time.Second
+)
+
+// WaitForVolume polls a Cinder volume until it reaches the "available" or
+// "in-use" status, backing off exponentially (starting at pollInterval,
+// capped at maxVolumePollInterval, with jitter) between attempts instead of
+// hammering the API at a fixed interval. It honors ctx cancellation and
+// fails fast on terminal states like "error" or "error_deleting" rather than
+// waiting out the full timeout, while transient API errors (5xx, timeouts)
+// are retried.
+//
+// A timeout or pollInterval of zero falls back to the package defaults.
+func WaitForVolume(ctx context.Context, client *gophercloud.ServiceClient, volumeID string, timeout, pollInterval time.Duration) error {
+	if timeout <= 0 {
+		timeout = defaultVolumeWaitTimeout
+	}
+	if pollInterval <= 0 {
+		pollInterval = defaultVolumePollInterval
+	}
+
+	deadline := time.Now().Add(timeout)
+	backoff := pollInterval
+
+	for {
+		volume, err := volumes.Get(client, volumeID).Extract()
+		if err != nil {
+			if !isTransientVolumeError(err) {
+				return fmt.Errorf("error getting volume %s: %s", volumeID, err)
+			}
+		} else {
+			switch volume.Status {
+			case "available", "in-use":
+				return nil
+			case "error", "error_deleting", "error_backing-up", "error_restoring", "error_extending":
+				return fmt.Errorf("volume %s entered status %q", volumeID, volume.Status)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for volume %s", timeout, volumeID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jitteredWait(backoff)):
+		}
+
+		backoff = nextBackoff(backoff)
+	}
+}
+
+// jitteredWait returns a random duration in [backoff/2, backoff], so
+// concurrent pollers don't all wake up and retry at the same instant.
+func jitteredWait(backoff time.Duration) time.Duration {
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// nextBackoff doubles backoff, capping it at maxVolumePollInterval.
+func nextBackoff(backoff time.Duration) time.Duration {
+	backoff *= 2
+	if backoff > maxVolumePollInterval {
+		backoff = maxVolumePollInterval
+	}
+	return backoff
+}
+
+// isTransientVolumeError reports whether err is a server-side or network
+// blip worth retrying, as opposed to a terminal failure.
+func isTransientVolumeError(err error) bool {
+	switch err.(type) {
+	case gophercloud.ErrDefault500, gophercloud.ErrDefault502, gophercloud.ErrDefault503, gophercloud.ErrDefault504, gophercloud.ErrTimeOut:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openstack
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+func TestIsTransientVolumeError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"500", gophercloud.ErrDefault500{}, true},
+		{"502", gophercloud.ErrDefault502{}, true},
+		{"503", gophercloud.ErrDefault503{}, true},
+		{"504", gophercloud.ErrDefault504{}, true},
+		{"timeout", gophercloud.ErrTimeOut{}, true},
+		{"404", gophercloud.ErrDefault404{}, false},
+		{"400", gophercloud.ErrDefault400{}, false},
+		{"plain error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientVolumeError(tt.err); got != tt.want {
+				t.Errorf("isTransientVolumeError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextBackoff(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want time.Duration
+	}{
+		{2 * time.Second, 4 * time.Second},
+		{16 * time.Second, 30 * time.Second},
+		{30 * time.Second, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := nextBackoff(tt.in); got != tt.want {
+			t.Errorf("nextBackoff(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestJitteredWait(t *testing.T) {
+	backoff := 10 * time.Second
+
+	for i := 0; i < 100; i++ {
+		wait := jitteredWait(backoff)
+		if wait < backoff/2 || wait > backoff {
+			t.Fatalf("jitteredWait(%s) = %s, want value in [%s, %s]", backoff, wait, backoff/2, backoff)
+		}
+	}
+}